@@ -0,0 +1,104 @@
+// Package apierr defines the typed errors handlers return, and the gin
+// middleware that turns them into a consistent JSON error envelope.
+package apierr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code identifies the class of failure, so clients can branch on it
+// without parsing Message.
+type Code string
+
+const (
+	ErrBadRequest          Code = "bad_request"
+	ErrUpstream            Code = "upstream_error"
+	ErrNotFound            Code = "not_found"
+	ErrRateLimited         Code = "rate_limited"
+	ErrUpstreamUnavailable Code = "upstream_unavailable"
+	ErrInternal            Code = "internal_error"
+	ErrUnauthorized        Code = "unauthorized"
+	ErrConflict            Code = "conflict"
+)
+
+// APIError is the error type handlers should return for anything that
+// should reach the client as a structured response rather than a bare
+// 500. HTTPStatus and Message are what the client sees; Cause is logged
+// but never serialized.
+type APIError struct {
+	Code       Code
+	Message    string
+	HTTPStatus int
+	Cause      error
+
+	// RetryAfter, when set, is echoed back as the Retry-After header.
+	RetryAfter string
+}
+
+func (e *APIError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Cause
+}
+
+// BadRequest wraps a request validation/binding error.
+func BadRequest(err error) *APIError {
+	return &APIError{Code: ErrBadRequest, Message: "invalid request", HTTPStatus: 400, Cause: err}
+}
+
+// Upstream wraps an unexpected failure talking to TMDB.
+func Upstream(err error) *APIError {
+	return &APIError{Code: ErrUpstream, Message: "upstream request failed", HTTPStatus: 502, Cause: err}
+}
+
+// NotFound indicates TMDB reported the resource doesn't exist.
+func NotFound(err error) *APIError {
+	return &APIError{Code: ErrNotFound, Message: "resource not found", HTTPStatus: 404, Cause: err}
+}
+
+// RateLimited indicates TMDB throttled us; retryAfter is forwarded as-is.
+func RateLimited(retryAfter string) *APIError {
+	return &APIError{
+		Code:       ErrRateLimited,
+		Message:    "rate limited by upstream",
+		HTTPStatus: 429,
+		RetryAfter: retryAfter,
+	}
+}
+
+// UpstreamUnavailable indicates TMDB itself is down or unreachable.
+func UpstreamUnavailable(err error) *APIError {
+	return &APIError{Code: ErrUpstreamUnavailable, Message: "upstream unavailable", HTTPStatus: 503, Cause: err}
+}
+
+// Internal wraps an unexpected internal failure (DB errors, panics, ...).
+func Internal(err error) *APIError {
+	return &APIError{Code: ErrInternal, Message: "internal server error", HTTPStatus: 500, Cause: err}
+}
+
+// Unauthorized indicates missing or invalid credentials.
+func Unauthorized(message string) *APIError {
+	return &APIError{Code: ErrUnauthorized, Message: message, HTTPStatus: 401}
+}
+
+// Conflict indicates the request collides with existing state (e.g. a
+// duplicate email on registration).
+func Conflict(message string) *APIError {
+	return &APIError{Code: ErrConflict, Message: message, HTTPStatus: 409}
+}
+
+// AsAPIError unwraps err into an *APIError, falling back to Internal if
+// it isn't one already.
+func AsAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return Internal(err)
+}
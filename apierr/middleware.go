@@ -0,0 +1,74 @@
+package apierr
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Ortieez/Show-tracker-extension-api/requestid"
+)
+
+// envelope is the response body shape for every error returned by the API.
+type envelope struct {
+	Error envelopeBody `json:"error"`
+}
+
+type envelopeBody struct {
+	Code      Code   `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// writeEnvelope renders err as the standard JSON error envelope.
+func writeEnvelope(c *gin.Context, err error) {
+	apiErr := AsAPIError(err)
+
+	if apiErr.RetryAfter != "" {
+		c.Header("Retry-After", apiErr.RetryAfter)
+	}
+
+	c.JSON(apiErr.HTTPStatus, envelope{Error: envelopeBody{
+		Code:      apiErr.Code,
+		Message:   apiErr.Message,
+		RequestID: requestid.Get(c),
+	}})
+}
+
+// Recovery recovers panics in downstream handlers and renders the usual
+// envelope directly, instead of gin's default plain-text 500. It can't
+// rely on ErrorHandler to do the rendering: a panic unwinds straight to
+// this deferred recover, past any post-c.Next() code in middleware
+// registered after Recovery.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(gin.DefaultErrorWriter, func(c *gin.Context, recovered interface{}) {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("%v", recovered)
+		}
+		writeEnvelope(c, Internal(err))
+		c.Abort()
+	})
+}
+
+// ErrorHandler renders the last error attached via c.Error as a
+// consistent JSON envelope. It must run after Recovery and before the
+// route handlers so it can see the errors they attach; panics are
+// rendered by Recovery and never reach it.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		writeEnvelope(c, c.Errors.Last().Err)
+	}
+}
+
+// Abort attaches err to the context and aborts the request; ErrorHandler
+// renders the response once the handler chain unwinds.
+func Abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Ortieez/Show-tracker-extension-api/apierr"
+)
+
+const jwtExpiry = time.Hour
+
+// claims is the JWT payload issued on login.
+type claims struct {
+	UserID int64 `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// AuthMiddleware parses the `Authorization: Bearer <token>` header, verifies
+// it against jwtSecret, and sets "userID" on the context. Requests without
+// a valid token are rejected with 401.
+func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := parseBearerToken(c, jwtSecret)
+		if !ok {
+			apierr.Abort(c, apierr.Unauthorized("missing or invalid authorization token"))
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware behaves like AuthMiddleware but lets the request
+// through when no token is present, so handlers can serve both anonymous
+// and authenticated clients and decorate the response when possible.
+func OptionalAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, ok := parseBearerToken(c, jwtSecret); ok {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	}
+}
+
+func parseBearerToken(c *gin.Context, jwtSecret string) (int64, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return 0, false
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return 0, false
+	}
+
+	token, err := jwt.ParseWithClaims(parts[1], &claims{}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, false
+	}
+
+	claimsVal, ok := token.Claims.(*claims)
+	if !ok {
+		return 0, false
+	}
+
+	return claimsVal.UserID, true
+}
+
+func signToken(userID int64, jwtSecret string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(jwtExpiry)),
+		},
+	})
+	return token.SignedString([]byte(jwtSecret))
+}
+
+func registerHandler(store *Store, jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RegisterRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		userID, err := store.CreateUser(req.Email, string(hash))
+		if err != nil {
+			if IsUniqueConstraintErr(err) {
+				apierr.Abort(c, apierr.Conflict("email already registered"))
+			} else {
+				apierr.Abort(c, apierr.Internal(err))
+			}
+			return
+		}
+
+		token, err := signToken(userID, jwtSecret)
+		if err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"token": token})
+	}
+}
+
+func loginHandler(store *Store, jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		user, err := store.GetUserByEmail(req.Email)
+		if err != nil {
+			apierr.Abort(c, apierr.Unauthorized("invalid email or password"))
+			return
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			apierr.Abort(c, apierr.Unauthorized("invalid email or password"))
+			return
+		}
+
+		token, err := signToken(user.ID, jwtSecret)
+		if err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"token": token})
+	}
+}
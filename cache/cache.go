@@ -0,0 +1,19 @@
+// Package cache provides a pluggable, TTL'd cache abstraction used to
+// avoid re-hitting TMDB for data we've already fetched.
+package cache
+
+import "time"
+
+// Store is a TTL'd key/value cache backend. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Get returns the cached value for key, and false if it's missing or
+	// has expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores val under key, to expire after ttl.
+	Set(key string, val []byte, ttl time.Duration)
+
+	// Delete removes key, if present.
+	Delete(key string)
+}
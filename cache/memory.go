@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// entry is a single cached value plus its expiry and the key, so it can
+// be found from the LRU list alone during eviction.
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is a bounded, in-memory LRU cache with a per-entry TTL.
+// Least-recently-used entries are evicted once the store exceeds
+// maxEntries.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+// NewMemoryStore creates an empty MemoryStore bounded to maxEntries.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if time.Now().After(e.expiresAt) {
+		s.removeElement(el)
+		return nil, false
+	}
+
+	s.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(key string, val []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = val
+		e.expiresAt = time.Now().Add(ttl)
+		s.ll.MoveToFront(el)
+		return
+	}
+
+	el := s.ll.PushFront(&entry{key: key, value: val, expiresAt: time.Now().Add(ttl)})
+	s.items[key] = el
+
+	if s.maxEntries > 0 && s.ll.Len() > s.maxEntries {
+		s.removeElement(s.ll.Back())
+	}
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.removeElement(el)
+	}
+}
+
+func (s *MemoryStore) removeElement(el *list.Element) {
+	e := el.Value.(*entry)
+	s.ll.Remove(el)
+	delete(s.items, e.key)
+}
+
+// snapshot is the on-disk representation written by SaveToFile. Expired
+// entries are skipped, both when saving and when loading.
+type snapshot struct {
+	Key       string    `json:"key"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SaveToFile persists all non-expired entries to path as JSON, most
+// recently used first.
+func (s *MemoryStore) SaveToFile(path string) error {
+	s.mu.Lock()
+	now := time.Now()
+	snapshots := make([]snapshot, 0, s.ll.Len())
+	for el := s.ll.Front(); el != nil; el = el.Next() {
+		e := el.Value.(*entry)
+		if now.After(e.expiresAt) {
+			continue
+		}
+		snapshots = append(snapshots, snapshot{Key: e.key, Value: e.value, ExpiresAt: e.expiresAt})
+	}
+	s.mu.Unlock()
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadFromFile restores entries previously written by SaveToFile,
+// skipping any that have since expired. It's a no-op if path doesn't exist.
+func (s *MemoryStore) LoadFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshots []snapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, snap := range snapshots {
+		if now.After(snap.ExpiresAt) {
+			continue
+		}
+		s.Set(snap.Key, snap.Value, snap.ExpiresAt.Sub(now))
+	}
+
+	return nil
+}
+
+// StartDebouncedPersist saves the store to path every interval, and
+// returns a stop function that performs one final save before returning.
+// Errors from individual saves are ignored; the next tick will retry.
+func (s *MemoryStore) StartDebouncedPersist(path string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.SaveToFile(path)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		s.SaveToFile(path)
+	}
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a Store backed by a shared Redis instance, so multiple
+// API replicas can share a cache instead of each keeping their own.
+type RedisStore struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// NewRedisStore connects to the Redis instance at redisURL (e.g.
+// "redis://localhost:6379/0").
+func NewRedisStore(redisURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisStore{client: redis.NewClient(opts), ctx: context.Background()}, nil
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(key string) ([]byte, bool) {
+	val, err := s.client.Get(s.ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// Set implements Store.
+func (s *RedisStore) Set(key string, val []byte, ttl time.Duration) {
+	s.client.Set(s.ctx, key, val, ttl)
+}
+
+// Delete implements Store.
+func (s *RedisStore) Delete(key string) {
+	s.client.Del(s.ctx, key)
+}
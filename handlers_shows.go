@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Ortieez/Show-tracker-extension-api/apierr"
+)
+
+// decorateDetail attaches the authenticated caller's favorite/library/
+// progress state to a cached or freshly-fetched TMDB details payload. It
+// merges into the raw TMDB fields rather than re-serializing a narrow
+// struct, so fields this API doesn't otherwise model (genres, seasons,
+// networks, ...) survive untouched. If the request is unauthenticated or
+// the user has no tracking state for the show, the body is returned
+// unmodified.
+func decorateDetail(store *Store, c *gin.Context, body []byte, showID int) interface{} {
+	userIDVal, ok := c.Get("userID")
+	if !ok {
+		return json.RawMessage(body)
+	}
+
+	var detail map[string]interface{}
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return json.RawMessage(body)
+	}
+
+	us, err := store.GetUserShow(userIDVal.(int64), showID)
+	if err != nil {
+		return json.RawMessage(body)
+	}
+
+	detail["favorite"] = us.Favorite
+	detail["library"] = us.Library
+	detail["season_number"] = us.SeasonNumber
+	detail["episode_number"] = us.EpisodeNumber
+
+	return detail
+}
+
+// listUserShowsHandler returns the authenticated user's tracked shows.
+func listUserShowsHandler(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int64)
+
+		shows, err := store.ListUserShows(userID)
+		if err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, shows)
+	}
+}
+
+// upsertUserShowHandler adds or updates a show's favorite/library flags.
+func upsertUserShowHandler(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int64)
+
+		var req UpsertShowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		if err := store.UpsertUserShow(userID, req.ShowID, req.Favorite, req.Library); err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// deleteUserShowHandler removes a show from the user's watchlist.
+func deleteUserShowHandler(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int64)
+
+		showID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		if err := store.DeleteUserShow(userID, showID); err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// updateProgressHandler records the season/episode the user watched up to.
+func updateProgressHandler(store *Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("userID").(int64)
+
+		showID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		var req ProgressRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
+			return
+		}
+
+		if err := store.SetProgress(userID, showID, req.SeasonNumber, req.EpisodeNumber); err != nil {
+			apierr.Abort(c, apierr.Internal(err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -3,15 +3,29 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
-	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+
+	"github.com/Ortieez/Show-tracker-extension-api/apierr"
+	"github.com/Ortieez/Show-tracker-extension-api/cache"
+	"github.com/Ortieez/Show-tracker-extension-api/requestid"
+	"github.com/Ortieez/Show-tracker-extension-api/tmdb"
+)
+
+const (
+	searchCacheTTL   = 6 * time.Hour
+	detailsCacheTTL  = 24 * time.Hour
+	trendingCacheTTL = 6 * time.Hour
+
+	cacheDebouncePersist = 30 * time.Second
 )
 
 type TVShow struct {
@@ -45,98 +59,131 @@ type IDRequest struct {
 	ID int `json:"id" binding:"required"`
 }
 
-var (
-	searchCacheFile  = "/app/cache/search_cache.json"
-	detailsCacheFile = "/app/cache/details_cache.json"
-	cacheMutex       sync.Mutex
-)
+type SeasonRequest struct {
+	ID           int `json:"id" binding:"required"`
+	SeasonNumber int `json:"season_number" binding:"gte=0"`
+}
 
-func loadCache(file string, cache interface{}) error {
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
+type EpisodeRequest struct {
+	ID            int `json:"id" binding:"required"`
+	SeasonNumber  int `json:"season_number" binding:"gte=0"`
+	EpisodeNumber int `json:"episode_number" binding:"gte=0"`
+}
 
-	if _, err := os.Stat(file); os.IsNotExist(err) {
-		return nil
+// newCacheStore builds the cache.Store selected by CACHE_BACKEND
+// ("memory", the default, or "redis"). A memory store is seeded from
+// cacheSnapshotFile, if present.
+func newCacheStore(envFile map[string]string) (cache.Store, error) {
+	switch backend := envFile["CACHE_BACKEND"]; backend {
+	case "redis":
+		redisURL := envFile["REDIS_URL"]
+		if redisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL is required when CACHE_BACKEND=redis")
+		}
+		return cache.NewRedisStore(redisURL)
+	case "", "memory":
+		store := cache.NewMemoryStore(10000)
+		if err := store.LoadFromFile(cacheSnapshotFile); err != nil {
+			fmt.Printf("Error loading cache snapshot: %v\n", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND %q", backend)
 	}
+}
 
-	data, err := os.ReadFile(file)
-	if err != nil {
-		return err
+// persistOnShutdown saves a MemoryStore to disk on a debounce timer and
+// once more on SIGTERM, so in-flight data survives restarts without
+// rewriting the snapshot on every cache hit.
+func persistOnShutdown(store cache.Store) {
+	memStore, ok := store.(*cache.MemoryStore)
+	if !ok {
+		return
 	}
 
-	return json.Unmarshal(data, cache)
-}
+	stopPersist := memStore.StartDebouncedPersist(cacheSnapshotFile, cacheDebouncePersist)
 
-func saveCache(file string, cache interface{}) error {
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
 
-	data, err := json.MarshalIndent(cache, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(file, data, 0644)
+	go func() {
+		<-sigCh
+		stopPersist()
+		os.Exit(0)
+	}()
 }
 
-func makeRequest(url string, bearerToken string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
-	}
+var cacheSnapshotFile = "/app/cache/snapshot.json"
 
-	req.Header.Add("accept", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", bearerToken))
-
-	res, err := http.DefaultClient.Do(req)
+func main() {
+	envFile, err := godotenv.Read(".env")
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		fmt.Printf("Error loading .env file: %v\n", err)
+		return
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
+	bearerToken := envFile["TMDB_BEARER_TOKEN"]
+	if bearerToken == "" {
+		fmt.Println("TMDB_BEARER_TOKEN environment variable is required")
+		return
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d: %s", res.StatusCode, string(body))
+	jwtSecret := envFile["JWT_SECRET"]
+	if jwtSecret == "" {
+		fmt.Println("JWT_SECRET environment variable is required")
+		return
 	}
 
-	return body, nil
-}
+	dbPath := envFile["DB_PATH"]
+	if dbPath == "" {
+		dbPath = "/app/data/tracker.db"
+	}
 
-func main() {
-	envFile, err := godotenv.Read(".env")
+	store, err := NewStore(dbPath)
 	if err != nil {
-		fmt.Printf("Error loading .env file: %v\n", err)
+		fmt.Printf("Error opening store: %v\n", err)
 		return
 	}
 
-	bearerToken := envFile["TMDB_BEARER_TOKEN"]
-	if bearerToken == "" {
-		fmt.Println("TMDB_BEARER_TOKEN environment variable is required")
+	cacheStore, err := newCacheStore(envFile)
+	if err != nil {
+		fmt.Printf("Error initializing cache: %v\n", err)
 		return
 	}
+	persistOnShutdown(cacheStore)
 
-	searchCache := make(map[string]json.RawMessage)
-	detailsCache := make(map[string]json.RawMessage)
+	tmdbClient := tmdb.NewClientFromEnv(bearerToken, envFile)
 
-	loadCache(searchCacheFile, &searchCache)
-	loadCache(detailsCacheFile, &detailsCache)
+	r := gin.New()
+	r.Use(gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | request_id=%v\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"), p.StatusCode, p.Latency,
+			p.ClientIP, p.Method, p.Path, p.Keys["request_id"], p.ErrorMessage)
+	}), requestid.Middleware(), apierr.Recovery(), apierr.ErrorHandler())
+
+	r.GET("/debug/stats", func(c *gin.Context) {
+		c.JSON(http.StatusOK, tmdbClient.Stats())
+	})
 
-	r := gin.Default()
+	r.POST("/auth/register", registerHandler(store, jwtSecret))
+	r.POST("/auth/login", loginHandler(store, jwtSecret))
+
+	users := r.Group("/users/me", AuthMiddleware(jwtSecret))
+	users.GET("/shows", listUserShowsHandler(store))
+	users.POST("/shows", upsertUserShowHandler(store))
+	users.DELETE("/shows/:id", deleteUserShowHandler(store))
+	users.PUT("/shows/:id/progress", updateProgressHandler(store))
 
 	r.POST("/tv/search", func(c *gin.Context) {
 		var request SearchRequest
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apierr.Abort(c, apierr.BadRequest(err))
 			return
 		}
 
-		queryKey := strings.ReplaceAll(request.Query, " ", "")
+		queryKey := "search:" + strings.ReplaceAll(request.Query, " ", "")
 
-		if cachedData, found := searchCache[queryKey]; found {
+		if cachedData, found := cacheStore.Get(queryKey); found {
 			c.JSON(http.StatusOK, json.RawMessage(cachedData))
 			return
 		}
@@ -144,42 +191,112 @@ func main() {
 		encodedQuery := url.QueryEscape(request.Query)
 		url := fmt.Sprintf("https://api.themoviedb.org/3/search/tv?include_adult=false&language=en-US&page=1&query=%s", encodedQuery)
 
-		body, err := makeRequest(url, bearerToken)
+		body, err := tmdbClient.Get(c.Request.Context(), url)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			apierr.Abort(c, err)
 			return
 		}
 
-		searchCache[queryKey] = body
-		saveCache(searchCacheFile, searchCache)
+		cacheStore.Set(queryKey, body, searchCacheTTL)
 
 		c.JSON(http.StatusOK, json.RawMessage(body))
 	})
 
-	r.POST("/tv/details", func(c *gin.Context) {
+	r.POST("/tv/details", OptionalAuthMiddleware(jwtSecret), func(c *gin.Context) {
 		var request IDRequest
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apierr.Abort(c, apierr.BadRequest(err))
 			return
 		}
 
-		idKey := fmt.Sprintf("%d", request.ID)
+		idKey := fmt.Sprintf("details:%d", request.ID)
 
-		if cachedData, found := detailsCache[idKey]; found {
-			c.JSON(http.StatusOK, json.RawMessage(cachedData))
+		body, found := cacheStore.Get(idKey)
+		if !found {
+			url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d?language=en-US", request.ID)
+
+			fetched, err := tmdbClient.Get(c.Request.Context(), url)
+			if err != nil {
+				apierr.Abort(c, err)
+				return
+			}
+
+			body = fetched
+			cacheStore.Set(idKey, body, detailsCacheTTL)
+		}
+
+		c.JSON(http.StatusOK, decorateDetail(store, c, body, request.ID))
+	})
+
+	r.POST("/tv/seasons", func(c *gin.Context) {
+		var request SeasonRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
 			return
 		}
 
-		url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d?language=en-US", request.ID)
+		key := fmt.Sprintf("season:%d:%d", request.ID, request.SeasonNumber)
 
-		body, err := makeRequest(url, bearerToken)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		body, found := cacheStore.Get(key)
+		if !found {
+			url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/season/%d?language=en-US", request.ID, request.SeasonNumber)
+
+			fetched, err := tmdbClient.Get(c.Request.Context(), url)
+			if err != nil {
+				apierr.Abort(c, err)
+				return
+			}
+
+			body = fetched
+			cacheStore.Set(key, body, detailsCacheTTL)
+		}
+
+		c.JSON(http.StatusOK, json.RawMessage(body))
+	})
+
+	r.POST("/tv/episode", func(c *gin.Context) {
+		var request EpisodeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			apierr.Abort(c, apierr.BadRequest(err))
 			return
 		}
 
-		detailsCache[idKey] = body
-		saveCache(detailsCacheFile, detailsCache)
+		key := fmt.Sprintf("episode:%d:%d:%d", request.ID, request.SeasonNumber, request.EpisodeNumber)
+
+		body, found := cacheStore.Get(key)
+		if !found {
+			url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/season/%d/episode/%d?language=en-US",
+				request.ID, request.SeasonNumber, request.EpisodeNumber)
+
+			fetched, err := tmdbClient.Get(c.Request.Context(), url)
+			if err != nil {
+				apierr.Abort(c, err)
+				return
+			}
+
+			body = fetched
+			cacheStore.Set(key, body, detailsCacheTTL)
+		}
+
+		c.JSON(http.StatusOK, json.RawMessage(body))
+	})
+
+	r.GET("/tv/trending", func(c *gin.Context) {
+		key := "trending:tv:week"
+
+		body, found := cacheStore.Get(key)
+		if !found {
+			url := "https://api.themoviedb.org/3/trending/tv/week?language=en-US"
+
+			fetched, err := tmdbClient.Get(c.Request.Context(), url)
+			if err != nil {
+				apierr.Abort(c, err)
+				return
+			}
+
+			body = fetched
+			cacheStore.Set(key, body, trendingCacheTTL)
+		}
 
 		c.JSON(http.StatusOK, json.RawMessage(body))
 	})
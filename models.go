@@ -0,0 +1,44 @@
+package main
+
+// User is a registered account that owns a personal watchlist.
+type User struct {
+	ID           int64  `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+}
+
+// UserShow is a single user's relationship to a TMDB show: whether it's
+// favorited, whether it's in their library, and how far they've watched.
+type UserShow struct {
+	UserID        int64 `json:"-"`
+	ShowID        int   `json:"show_id"`
+	Favorite      bool  `json:"favorite"`
+	Library       bool  `json:"library"`
+	SeasonNumber  int   `json:"season_number"`
+	EpisodeNumber int   `json:"episode_number"`
+}
+
+// UpsertShowRequest is the body for POST /users/me/shows.
+type UpsertShowRequest struct {
+	ShowID   int  `json:"show_id" binding:"required"`
+	Favorite bool `json:"favorite"`
+	Library  bool `json:"library"`
+}
+
+// ProgressRequest is the body for PUT /users/me/shows/:id/progress.
+type ProgressRequest struct {
+	SeasonNumber  int `json:"season_number" binding:"gte=0"`
+	EpisodeNumber int `json:"episode_number" binding:"gte=0"`
+}
+
+// RegisterRequest is the body for POST /auth/register.
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// LoginRequest is the body for POST /auth/login.
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
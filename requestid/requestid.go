@@ -0,0 +1,35 @@
+// Package requestid tags every request with a unique ID, so a client's
+// error response and the corresponding server log line can be correlated.
+package requestid
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const headerName = "X-Request-ID"
+
+// contextKey is the gin context key the ID is stored under.
+const contextKey = "request_id"
+
+// Middleware assigns a request ID, reusing one supplied by the caller in
+// the X-Request-ID header if present, and echoes it back on the response.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(headerName)
+		if id == "" {
+			id = uuid.NewString()
+		}
+
+		c.Set(contextKey, id)
+		c.Header(headerName, id)
+		c.Next()
+	}
+}
+
+// Get returns the request ID set by Middleware, or "" if it wasn't run.
+func Get(c *gin.Context) string {
+	id, _ := c.Get(contextKey)
+	idStr, _ := id.(string)
+	return idStr
+}
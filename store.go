@@ -0,0 +1,159 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNotFound is returned by Store lookups that find no matching row.
+var ErrNotFound = errors.New("not found")
+
+// IsUniqueConstraintErr reports whether err is a SQLite UNIQUE constraint
+// violation, e.g. from CreateUser on an already-registered email.
+func IsUniqueConstraintErr(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	return false
+}
+
+// Store is the persistence layer for users and their per-show tracking
+// state. It's backed by SQLite, but kept narrow enough to swap later.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore opens (and migrates) the SQLite database at path.
+func NewStore(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS user_shows (
+			user_id INTEGER NOT NULL,
+			show_id INTEGER NOT NULL,
+			favorite INTEGER NOT NULL DEFAULT 0,
+			library INTEGER NOT NULL DEFAULT 0,
+			season_number INTEGER NOT NULL DEFAULT 0,
+			episode_number INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, show_id)
+		);
+	`)
+	return err
+}
+
+// CreateUser inserts a new user and returns its generated ID.
+func (s *Store) CreateUser(email, passwordHash string) (int64, error) {
+	res, err := s.db.Exec(`INSERT INTO users (email, password_hash) VALUES (?, ?)`, email, passwordHash)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetUserByEmail looks up a user by email, returning ErrNotFound if absent.
+func (s *Store) GetUserByEmail(email string) (*User, error) {
+	row := s.db.QueryRow(`SELECT id, email, password_hash FROM users WHERE email = ?`, email)
+
+	var u User
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// GetUserShow returns the calling user's tracking state for a show.
+func (s *Store) GetUserShow(userID int64, showID int) (*UserShow, error) {
+	row := s.db.QueryRow(`
+		SELECT user_id, show_id, favorite, library, season_number, episode_number
+		FROM user_shows WHERE user_id = ? AND show_id = ?`, userID, showID)
+
+	var us UserShow
+	if err := row.Scan(&us.UserID, &us.ShowID, &us.Favorite, &us.Library, &us.SeasonNumber, &us.EpisodeNumber); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &us, nil
+}
+
+// ListUserShows returns every show the user has tracking state for,
+// including shows with only recorded progress and no favorite/library flag.
+func (s *Store) ListUserShows(userID int64) ([]UserShow, error) {
+	rows, err := s.db.Query(`
+		SELECT user_id, show_id, favorite, library, season_number, episode_number
+		FROM user_shows WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shows []UserShow
+	for rows.Next() {
+		var us UserShow
+		if err := rows.Scan(&us.UserID, &us.ShowID, &us.Favorite, &us.Library, &us.SeasonNumber, &us.EpisodeNumber); err != nil {
+			return nil, err
+		}
+		shows = append(shows, us)
+	}
+
+	return shows, rows.Err()
+}
+
+// UpsertUserShow creates or updates a user's favorite/library flags for a show.
+func (s *Store) UpsertUserShow(userID int64, showID int, favorite, library bool) error {
+	_, err := s.db.Exec(`
+		INSERT INTO user_shows (user_id, show_id, favorite, library)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, show_id) DO UPDATE SET favorite = excluded.favorite, library = excluded.library`,
+		userID, showID, favorite, library)
+	return err
+}
+
+// DeleteUserShow removes a show from the user's watchlist entirely.
+func (s *Store) DeleteUserShow(userID int64, showID int) error {
+	_, err := s.db.Exec(`DELETE FROM user_shows WHERE user_id = ? AND show_id = ?`, userID, showID)
+	return err
+}
+
+// SetProgress records the season/episode the user has watched up to. It
+// upserts so progress can be recorded before a show has been favorited
+// or added to the library.
+func (s *Store) SetProgress(userID int64, showID, season, episode int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO user_shows (user_id, show_id, season_number, episode_number)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, show_id) DO UPDATE SET season_number = excluded.season_number, episode_number = excluded.episode_number`,
+		userID, showID, season, episode)
+	return err
+}
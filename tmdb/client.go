@@ -0,0 +1,201 @@
+// Package tmdb wraps outbound calls to the TMDB API with rate shaping
+// and in-flight request coalescing, so cache misses under load don't
+// turn into a burst of duplicate upstream calls.
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+
+	"github.com/Ortieez/Show-tracker-extension-api/apierr"
+	"github.com/Ortieez/Show-tracker-extension-api/useragent"
+)
+
+const (
+	defaultRPS   = 40
+	defaultBurst = 40
+
+	maxAttempts      = 5
+	initialBackoff   = 500 * time.Millisecond
+	maxBackoffJitter = 250 * time.Millisecond
+)
+
+// Stats is a snapshot of a Client's request coalescing behavior, served
+// at GET /debug/stats.
+type Stats struct {
+	Inflight    int64 `json:"inflight"`
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	RateLimited int64 `json:"rate_limited_429s"`
+}
+
+// Client issues GET requests to TMDB, rate-limited by a token bucket and
+// deduplicated via singleflight so concurrent requests for the same URL
+// share one round-trip.
+type Client struct {
+	bearerToken string
+	httpClient  *http.Client
+	limiter     *rate.Limiter
+	group       singleflight.Group
+
+	inflight    int64
+	hits        int64
+	misses      int64
+	rateLimited int64
+}
+
+// NewClient builds a Client rate-limited to rps requests/sec with the
+// given burst.
+func NewClient(bearerToken string, rps float64, burst int) *Client {
+	return &Client{
+		bearerToken: bearerToken,
+		httpClient:  http.DefaultClient,
+		limiter:     rate.NewLimiter(rate.Limit(rps), burst),
+	}
+}
+
+// NewClientFromEnv builds a Client configured from TMDB_RPS (default 40).
+// The burst is always defaultBurst, whatever TMDB_RPS is set to.
+func NewClientFromEnv(bearerToken string, envFile map[string]string) *Client {
+	rps := float64(defaultRPS)
+	if raw := envFile["TMDB_RPS"]; raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil && parsed > 0 {
+			rps = parsed
+		}
+	}
+	return NewClient(bearerToken, rps, defaultBurst)
+}
+
+// Stats returns a point-in-time snapshot of the client's counters.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Inflight:    atomic.LoadInt64(&c.inflight),
+		Hits:        atomic.LoadInt64(&c.hits),
+		Misses:      atomic.LoadInt64(&c.misses),
+		RateLimited: atomic.LoadInt64(&c.rateLimited),
+	}
+}
+
+// Get fetches url, sharing the round-trip with any other concurrent Get
+// for the same url.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	atomic.AddInt64(&c.inflight, 1)
+	defer atomic.AddInt64(&c.inflight, -1)
+
+	executed := false
+	v, err, _ := c.group.Do(url, func() (interface{}, error) {
+		executed = true
+		return c.fetch(ctx, url)
+	})
+
+	if executed {
+		atomic.AddInt64(&c.misses, 1)
+	} else {
+		atomic.AddInt64(&c.hits, 1)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// fetch performs the actual upstream round-trip, retrying on 429 with
+// exponential backoff honoring Retry-After, up to maxAttempts.
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	backoff := initialBackoff
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, apierr.UpstreamUnavailable(fmt.Errorf("rate limiter: %w", err))
+		}
+
+		body, err := c.do(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+
+		apiErr := apierr.AsAPIError(err)
+		if apiErr.Code != apierr.ErrRateLimited {
+			return nil, err
+		}
+
+		atomic.AddInt64(&c.rateLimited, 1)
+
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		wait := retryAfterDuration(apiErr.RetryAfter)
+		if wait == 0 {
+			wait = backoff
+		}
+		wait += time.Duration(rand.Int63n(int64(maxBackoffJitter)))
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, apierr.UpstreamUnavailable(ctx.Err())
+		}
+
+		backoff *= 2
+	}
+
+	return nil, apierr.RateLimited("")
+}
+
+func (c *Client) do(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("error creating request: %w", err))
+	}
+
+	req.Header.Add("accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+	req.Header.Add("User-Agent", useragent.Random())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, apierr.UpstreamUnavailable(fmt.Errorf("error making request: %w", err))
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, apierr.Upstream(fmt.Errorf("error reading response: %w", err))
+	}
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+		return body, nil
+	case res.StatusCode == http.StatusTooManyRequests:
+		return nil, apierr.RateLimited(res.Header.Get("Retry-After"))
+	case res.StatusCode == http.StatusNotFound:
+		return nil, apierr.NotFound(fmt.Errorf("TMDB returned 404: %s", body))
+	default:
+		return nil, apierr.Upstream(fmt.Errorf("TMDB returned status %d: %s", res.StatusCode, body))
+	}
+}
+
+// retryAfterDuration parses a Retry-After header (seconds form only,
+// which is what TMDB sends) into a Duration, returning 0 if absent or
+// unparseable.
+func retryAfterDuration(retryAfter string) time.Duration {
+	if retryAfter == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(retryAfter)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
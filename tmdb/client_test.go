@@ -0,0 +1,123 @@
+package tmdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ortieez/Show-tracker-extension-api/apierr"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient("test-token", 1000, 1000)
+	c.httpClient = server.Client()
+
+	return c, server
+}
+
+func TestClientGetReturnsBody(t *testing.T) {
+	c, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	body, err := c.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("Get() body = %q", body)
+	}
+}
+
+func TestClientGetCoalescesConcurrentRequests(t *testing.T) {
+	const n = 20
+
+	var calls int64
+	release := make(chan struct{})
+	arrived := make(chan struct{}, n)
+
+	c, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		arrived <- struct{}{}
+		<-release
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(context.Background(), server.URL); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		}()
+	}
+
+	<-arrived
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 upstream call, got %d", calls)
+	}
+
+	stats := c.Stats()
+	if stats.Misses != 1 {
+		t.Fatalf("expected 1 miss, got %d", stats.Misses)
+	}
+	if stats.Hits != 19 {
+		t.Fatalf("expected 19 hits, got %d", stats.Hits)
+	}
+}
+
+func TestClientGetRetriesOn429(t *testing.T) {
+	var calls int64
+	c, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	body, err := c.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("Get() body = %q", body)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls (1 retry), got %d", calls)
+	}
+
+	if stats := c.Stats(); stats.RateLimited != 1 {
+		t.Fatalf("expected 1 recorded rate limit, got %d", stats.RateLimited)
+	}
+}
+
+func TestClientGetPropagatesNotFound(t *testing.T) {
+	c, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := c.Get(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("expected an error for a 404 response")
+	}
+	if code := apierr.AsAPIError(err).Code; code != apierr.ErrNotFound {
+		t.Fatalf("expected code %q, got %q", apierr.ErrNotFound, code)
+	}
+}
@@ -0,0 +1,182 @@
+// Package useragent generates plausible, rotating browser User-Agent
+// strings so outbound TMDB requests aren't trivially fingerprinted as
+// coming from a single backend service.
+package useragent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const caniuseURL = "https://raw.githubusercontent.com/Fyrd/caniuse/master/fulldata-json/data-2.0.json"
+
+const cacheTTL = 24 * time.Hour
+
+// BrowserVersion is a single browser release and its global usage share,
+// as reported by caniuse.
+type BrowserVersion struct {
+	Version string
+	Global  float64
+}
+
+// fallbackFirefox and fallbackChrome are used when the caniuse fetch
+// fails, so Random() always returns something plausible.
+var (
+	fallbackFirefox = []BrowserVersion{
+		{Version: "115.0", Global: 1},
+		{Version: "117.0", Global: 1},
+		{Version: "120.0", Global: 1},
+	}
+	fallbackChrome = []BrowserVersion{
+		{Version: "115.0.0.0", Global: 1},
+		{Version: "120.0.0.0", Global: 1},
+		{Version: "124.0.0.0", Global: 1},
+	}
+)
+
+var platforms = []string{
+	"Windows NT 10.0; Win64; x64",
+	"Macintosh; Intel Mac OS X 10_15_7",
+	"X11; Linux x86_64",
+}
+
+type caniuseData struct {
+	Agents struct {
+		Firefox struct {
+			UsageGlobal map[string]float64 `json:"usage_global"`
+		} `json:"firefox"`
+		Chrome struct {
+			UsageGlobal map[string]float64 `json:"usage_global"`
+		} `json:"chrome"`
+	} `json:"agents"`
+}
+
+// cache holds the most recently fetched version pools, guarded by mu and
+// refreshed lazily once it's older than cacheTTL.
+type cache struct {
+	mu        sync.RWMutex
+	firefox   []BrowserVersion
+	chrome    []BrowserVersion
+	fetchedAt time.Time
+	client    *http.Client
+	url       string
+}
+
+var defaultCache = &cache{
+	client: http.DefaultClient,
+	url:    caniuseURL,
+}
+
+// Random returns a plausible, randomly-chosen User-Agent string, weighted
+// by real-world browser/version popularity.
+func Random() string {
+	return defaultCache.random()
+}
+
+func (c *cache) random() string {
+	firefox, chrome := c.versions()
+
+	if rand.Intn(2) == 0 {
+		v := weightedVersion(firefox)
+		return fmt.Sprintf("Mozilla/5.0 (%s; rv:%s) Gecko/20100101 Firefox/%s",
+			randomPlatform(), v, v)
+	}
+
+	v := weightedVersion(chrome)
+	return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s Safari/537.36",
+		randomPlatform(), v)
+}
+
+func randomPlatform() string {
+	return platforms[rand.Intn(len(platforms))]
+}
+
+// versions returns the current firefox/chrome version pools, refreshing
+// them from caniuse if the cache has expired.
+func (c *cache) versions() ([]BrowserVersion, []BrowserVersion) {
+	c.mu.RLock()
+	fresh := time.Since(c.fetchedAt) < cacheTTL && len(c.firefox) > 0 && len(c.chrome) > 0
+	firefox, chrome := c.firefox, c.chrome
+	c.mu.RUnlock()
+
+	if fresh {
+		return firefox, chrome
+	}
+
+	firefox, chrome, err := c.fetch()
+	if err != nil || len(firefox) == 0 || len(chrome) == 0 {
+		return fallbackFirefox, fallbackChrome
+	}
+
+	c.mu.Lock()
+	c.firefox, c.chrome, c.fetchedAt = firefox, chrome, time.Now()
+	c.mu.Unlock()
+
+	return firefox, chrome
+}
+
+func (c *cache) fetch() ([]BrowserVersion, []BrowserVersion, error) {
+	res, err := c.client.Get(c.url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching caniuse data: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("caniuse returned status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading caniuse response: %w", err)
+	}
+
+	var data caniuseData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, nil, fmt.Errorf("parsing caniuse response: %w", err)
+	}
+
+	return toVersions(data.Agents.Firefox.UsageGlobal), toVersions(data.Agents.Chrome.UsageGlobal), nil
+}
+
+func toVersions(usage map[string]float64) []BrowserVersion {
+	versions := make([]BrowserVersion, 0, len(usage))
+	for version, global := range usage {
+		if global <= 0 {
+			continue
+		}
+		versions = append(versions, BrowserVersion{Version: version, Global: global})
+	}
+	return versions
+}
+
+// weightedVersion samples a version, weighted by its Global usage share.
+func weightedVersion(versions []BrowserVersion) string {
+	if len(versions) == 0 {
+		return "120.0"
+	}
+
+	var total float64
+	for _, v := range versions {
+		total += v.Global
+	}
+
+	if total <= 0 {
+		return versions[rand.Intn(len(versions))].Version
+	}
+
+	target := rand.Float64() * total
+	for _, v := range versions {
+		target -= v.Global
+		if target <= 0 {
+			return v.Version
+		}
+	}
+
+	return versions[len(versions)-1].Version
+}
@@ -0,0 +1,87 @@
+package useragent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const stubCaniuseJSON = `{
+	"agents": {
+		"firefox": {
+			"usage_global": {"115.0": 1.5, "120.0": 10.2}
+		},
+		"chrome": {
+			"usage_global": {"115.0.0.0": 2.1, "124.0.0.0": 30.4}
+		}
+	}
+}`
+
+func newStubCache(t *testing.T, body string, status int) *cache {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &cache{client: server.Client(), url: server.URL}
+}
+
+func TestCacheFetchParsesCaniuseData(t *testing.T) {
+	c := newStubCache(t, stubCaniuseJSON, http.StatusOK)
+
+	firefox, chrome, err := c.fetch()
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+
+	if len(firefox) != 2 {
+		t.Fatalf("expected 2 firefox versions, got %d", len(firefox))
+	}
+	if len(chrome) != 2 {
+		t.Fatalf("expected 2 chrome versions, got %d", len(chrome))
+	}
+}
+
+func TestCacheVersionsFallsBackOnFetchError(t *testing.T) {
+	c := newStubCache(t, "not json", http.StatusOK)
+
+	firefox, chrome := c.versions()
+
+	if len(firefox) != len(fallbackFirefox) || len(chrome) != len(fallbackChrome) {
+		t.Fatalf("expected fallback version pools on parse failure")
+	}
+}
+
+func TestCacheVersionsFallsBackOnUpstreamError(t *testing.T) {
+	c := newStubCache(t, "", http.StatusInternalServerError)
+
+	firefox, chrome := c.versions()
+
+	if len(firefox) != len(fallbackFirefox) || len(chrome) != len(fallbackChrome) {
+		t.Fatalf("expected fallback version pools on upstream error")
+	}
+}
+
+func TestCacheRandomProducesPlausibleUA(t *testing.T) {
+	c := newStubCache(t, stubCaniuseJSON, http.StatusOK)
+
+	for i := 0; i < 20; i++ {
+		ua := c.random()
+		if !strings.HasPrefix(ua, "Mozilla/5.0") {
+			t.Fatalf("expected UA to start with Mozilla/5.0, got %q", ua)
+		}
+		if !strings.Contains(ua, "Firefox/") && !strings.Contains(ua, "Chrome/") {
+			t.Fatalf("expected UA to mention Firefox or Chrome, got %q", ua)
+		}
+	}
+}
+
+func TestWeightedVersionEmptyPool(t *testing.T) {
+	if v := weightedVersion(nil); v == "" {
+		t.Fatalf("expected a non-empty fallback version")
+	}
+}